@@ -0,0 +1,22 @@
+// Package pkg holds small shared utilities used across the go-mcp modules.
+package pkg
+
+import "sync"
+
+// SyncMap is a type-safe wrapper around sync.Map.
+type SyncMap[T any] struct {
+	m sync.Map
+}
+
+func (s *SyncMap[T]) Load(key string) (T, bool) {
+	v, ok := s.m.Load(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return v.(T), true
+}
+
+func (s *SyncMap[T]) Store(key string, value T) {
+	s.m.Store(key, value)
+}
@@ -0,0 +1,28 @@
+package protocol
+
+// Object is the top-level type every tool's InputSchema is declared with.
+// It's the same literal as ObjectT; the two names exist because InputSchema
+// predates Property in the original protocol package.
+const Object = ObjectT
+
+// InputSchema is the JSON Schema describing the arguments a tool accepts,
+// as sent to MCP clients in a tool's definition.
+type InputSchema struct {
+	Type       DataType             `json:"type"`
+	Properties map[string]*Property `json:"properties,omitempty"`
+	Required   []string             `json:"required,omitempty"`
+
+	// AdditionalProperties mirrors the JSON Schema keyword of the same name;
+	// set via a struct-level `mcp:"additionalProperties=false"` tag.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
+
+	// Defs holds named sub-schemas referenced from Properties via
+	// {"$ref": "#/$defs/Name"}, used to represent self- and
+	// mutually-recursive struct types without looping forever.
+	Defs map[string]*Property `json:"$defs,omitempty"`
+
+	// Aliases maps a canonical property name to the alternate names it may
+	// also be supplied under, gathered from `mcp:"alias=..."` struct tags.
+	// Consulted by BindArguments; not part of the JSON Schema itself.
+	Aliases map[string][]string `json:"-"`
+}
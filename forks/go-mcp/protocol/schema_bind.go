@@ -0,0 +1,88 @@
+package protocol
+
+import "encoding/json"
+
+// BindArguments decodes raw tool-call arguments into dst according to schema:
+// it fills in any `default` values for properties missing from raw, applies
+// field aliases registered via `mcp:"alias=..."` tags, enforces `required`
+// and `enum` constraints, and only then unmarshals into dst. Every
+// constraint violation is collected into the returned *ValidationError
+// instead of stopping at the first one.
+func BindArguments(schema *InputSchema, raw json.RawMessage, dst any) error {
+	args := make(map[string]any)
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return &ValidationError{Violations: []Violation{{Pointer: "", Message: "invalid JSON: " + err.Error()}}}
+		}
+	}
+
+	applyAliases(schema.Properties, schema.Aliases, schema.Defs, args)
+	applyDefaults(schema.Properties, args)
+
+	if err := Validate(schema, args); err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(args)
+	if err != nil {
+		return &ValidationError{Violations: []Violation{{Pointer: "", Message: err.Error()}}}
+	}
+	if err := json.Unmarshal(merged, dst); err != nil {
+		return &ValidationError{Violations: []Violation{{Pointer: "", Message: err.Error()}}}
+	}
+	return nil
+}
+
+// applyAliases rewrites any key in args that matches a registered alias to
+// its canonical property name, so downstream lookups only ever see the
+// canonical name. It recurses into nested objects (and arrays of objects),
+// consulting each level's own Property.Aliases rather than a single flat
+// map - otherwise two nested structs with same-named but differently
+// aliased fields would clobber each other's alias list, and an alias on a
+// field of a nested struct would never be applied at all.
+func applyAliases(properties map[string]*Property, aliases map[string][]string, defs map[string]*Property, args map[string]any) {
+	for canonical, names := range aliases {
+		if _, ok := args[canonical]; ok {
+			continue
+		}
+		for _, alias := range names {
+			if v, ok := args[alias]; ok {
+				args[canonical] = v
+				delete(args, alias)
+				break
+			}
+		}
+	}
+
+	for name, prop := range properties {
+		resolved := resolveRef(prop, defs)
+		if resolved == nil {
+			continue
+		}
+		switch v := args[name].(type) {
+		case map[string]any:
+			applyAliases(resolved.Properties, resolved.Aliases, defs, v)
+		case []any:
+			items := resolveRef(resolved.Items, defs)
+			if items == nil {
+				continue
+			}
+			for _, elem := range v {
+				if m, ok := elem.(map[string]any); ok {
+					applyAliases(items.Properties, items.Aliases, defs, m)
+				}
+			}
+		}
+	}
+}
+
+func applyDefaults(properties map[string]*Property, args map[string]any) {
+	for name, prop := range properties {
+		if _, ok := args[name]; ok {
+			continue
+		}
+		if prop.Default != nil {
+			args[name] = prop.Default
+		}
+	}
+}
@@ -0,0 +1,743 @@
+package protocol
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ThinkInAIXYZ/go-mcp/pkg"
+)
+
+type DataType string
+
+const (
+	ObjectT DataType = "object"
+	Number  DataType = "number"
+	Integer DataType = "integer"
+	String  DataType = "string"
+	Array   DataType = "array"
+	Null    DataType = "null"
+	Boolean DataType = "boolean"
+)
+
+type Property struct {
+	Type DataType `json:"type,omitempty"`
+	// Description is the description of the schema.
+	Description string `json:"description,omitempty"`
+	// Items specifies which data type an array contains, if the schema type is Array.
+	Items *Property `json:"items,omitempty"`
+	// Properties describes the properties of an object, if the schema type is Object.
+	Properties map[string]*Property `json:"properties,omitempty"`
+	Required   []string             `json:"required,omitempty"`
+	Enum       []any                `json:"enum,omitempty"`
+	// Default specifies the default value for the property.
+	Default any `json:"default,omitempty"`
+
+	// String validation keywords.
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	// Format is a JSON Schema format hint, e.g. "date-time", "email", "uri", "uuid", "ipv4", "ipv6".
+	Format string `json:"format,omitempty"`
+
+	// Numeric validation keywords.
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+
+	// Array validation keywords.
+	MinItems    *int `json:"minItems,omitempty"`
+	MaxItems    *int `json:"maxItems,omitempty"`
+	UniqueItems bool `json:"uniqueItems,omitempty"`
+
+	// AdditionalProperties mirrors the JSON Schema keyword of the same name. Only
+	// emitted when a struct opts in via the `mcp:"additionalProperties=false"` tag
+	// on a blank identifier field.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
+	// Const restricts the property to a single fixed value.
+	Const any `json:"const,omitempty"`
+
+	// Union keywords. Each entry is a full sub-schema.
+	OneOf []*Property `json:"oneOf,omitempty"`
+	AnyOf []*Property `json:"anyOf,omitempty"`
+	AllOf []*Property `json:"allOf,omitempty"`
+	Not   *Property   `json:"not,omitempty"`
+
+	// Ref points at a definition under InputSchema.Defs, e.g. "#/$defs/Node".
+	// When set, it replaces the rest of the schema for this property.
+	Ref string `json:"$ref,omitempty"`
+
+	// Annotations carried over from the `mcp` struct tag.
+	ReadOnly   bool `json:"readOnly,omitempty"`
+	WriteOnly  bool `json:"writeOnly,omitempty"`
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// Aliases maps a canonical property name declared on this object to the
+	// alternate names it may also be supplied under, gathered from
+	// `mcp:"alias=..."` tags on this object's own fields. Scoped to this
+	// object (unlike InputSchema.Aliases at the root) so that two structs
+	// with same-named fields never clobber each other's alias list.
+	Aliases map[string][]string `json:"-"`
+}
+
+var schemaCache = pkg.SyncMap[*InputSchema]{}
+
+func generateSchemaFromReqStruct(v any) (*InputSchema, error) {
+	t := reflect.TypeOf(v)
+	for t.Kind() != reflect.Struct {
+		if t.Kind() != reflect.Ptr {
+			return nil, fmt.Errorf("invalid type %v", t)
+		}
+		t = t.Elem()
+	}
+
+	typeUID := getTypeUUID(t)
+	if schema, ok := schemaCache.Load(typeUID); ok {
+		return schema, nil
+	}
+
+	schema := &InputSchema{Type: Object}
+
+	ctx := newGenContext()
+	ctx.defNames[typeUID] = ctx.reserveDefName(t.Name())
+	property, err := reflectSchemaByObject(t, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schema.Properties = property.Properties
+	schema.Required = property.Required
+	if property.AdditionalProperties != nil {
+		schema.AdditionalProperties = property.AdditionalProperties
+	}
+	if len(property.Aliases) > 0 {
+		schema.Aliases = property.Aliases
+	}
+
+	// If the root type was reached again while reflecting its own fields (a
+	// self- or mutually-recursive struct), it was emitted as a "#/$defs/Name"
+	// reference. Make sure that definition actually resolves to the root
+	// schema we just built.
+	if ctx.referenced[typeUID] {
+		ctx.defs[ctx.defNames[typeUID]] = &Property{
+			Type:       ObjectT,
+			Properties: schema.Properties,
+			Required:   schema.Required,
+		}
+	}
+	if len(ctx.defs) > 0 {
+		schema.Defs = ctx.defs
+	}
+
+	schemaCache.Store(typeUID, schema)
+	return schema, nil
+}
+
+func getTypeUUID(t reflect.Type) string {
+	if t.PkgPath() != "" && t.Name() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	// fallback for unnamed types (like anonymous struct)
+	return t.String()
+}
+
+// genContext tracks state for a single top-level schema generation call so
+// that recursive/self-referential structs terminate instead of looping
+// forever, and so every named struct type is only fully expanded once.
+type genContext struct {
+	// referenced holds type UUIDs that were turned into a "$ref" at least once.
+	referenced map[string]bool
+	// defs accumulates the emitted definitions, keyed by the name reserved for
+	// them via defNames, that back InputSchema.Defs.
+	defs map[string]*Property
+	// defNames maps a type UUID to the $defs name reserved for it. Reserving
+	// by UUID (package path + name) rather than the bare type name keeps two
+	// distinct types that happen to share a name (e.g. pkgA.Node and
+	// pkgB.Node) from colliding on the same "#/$defs/Node" entry.
+	defNames map[string]string
+	// usedDefNames tracks which $defs names are currently taken, so a name
+	// collision falls back to a disambiguated suffix instead of overwriting.
+	usedDefNames map[string]bool
+}
+
+func newGenContext() *genContext {
+	return &genContext{
+		referenced:   make(map[string]bool),
+		defs:         make(map[string]*Property),
+		defNames:     make(map[string]string),
+		usedDefNames: make(map[string]bool),
+	}
+}
+
+// reserveDefName assigns and returns a unique $defs name for baseName,
+// disambiguating with a numeric suffix if it's already taken.
+func (ctx *genContext) reserveDefName(baseName string) string {
+	name := baseName
+	for n := 2; ctx.usedDefNames[name]; n++ {
+		name = fmt.Sprintf("%s%d", baseName, n)
+	}
+	ctx.usedDefNames[name] = true
+	return name
+}
+
+// fieldDirectives is the parsed form of the `mcp:"..."` struct tag.
+type fieldDirectives struct {
+	name          string
+	aliases       []string
+	flatten       bool
+	flattenPrefix string
+	readOnly      bool
+	writeOnly     bool
+	deprecated    bool
+}
+
+func parseFieldDirectives(tag string) fieldDirectives {
+	var d fieldDirectives
+	if tag == "" {
+		return d
+	}
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "flatten":
+			d.flatten = true
+		case strings.HasPrefix(part, "flatten="):
+			d.flatten = true
+			d.flattenPrefix = strings.TrimPrefix(part, "flatten=")
+		case part == "readonly":
+			d.readOnly = true
+		case part == "writeonly":
+			d.writeOnly = true
+		case part == "deprecated":
+			d.deprecated = true
+		case strings.HasPrefix(part, "name="):
+			d.name = strings.TrimPrefix(part, "name=")
+		case strings.HasPrefix(part, "alias="):
+			d.aliases = append(d.aliases, strings.Split(strings.TrimPrefix(part, "alias="), "|")...)
+		}
+	}
+	return d
+}
+
+func reflectSchemaByObject(t reflect.Type, ctx *genContext) (*Property, error) {
+	var (
+		properties      = make(map[string]*Property)
+		requiredFields  = make([]string, 0)
+		anonymousFields = make([]reflect.StructField, 0)
+		additionalProps *bool
+		// aliases is scoped to this object, not the whole genContext, so that
+		// two distinct structs with same-named fields don't clobber each
+		// other's alias list, and so BindArguments can walk it alongside the
+		// matching level of nested argument data.
+		aliases = make(map[string][]string)
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Name == "_" {
+			if v := field.Tag.Get("mcp"); v != "" {
+				for _, part := range strings.Split(v, ",") {
+					if strings.TrimSpace(part) == "additionalProperties=false" {
+						no := false
+						additionalProps = &no
+					}
+				}
+			}
+			continue
+		}
+
+		if field.Anonymous {
+			anonymousFields = append(anonymousFields, field)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		required := true
+		if jsonTag == "" {
+			jsonTag = field.Name
+		}
+		if strings.HasSuffix(jsonTag, ",omitempty") {
+			jsonTag = strings.TrimSuffix(jsonTag, ",omitempty")
+			required = false
+		}
+
+		directives := parseFieldDirectives(field.Tag.Get("mcp"))
+		propName := jsonTag
+		if directives.name != "" {
+			propName = directives.name
+		}
+
+		// A field carrying a `oneOf:"TypeA,TypeB"` tag is handled entirely by
+		// its listed branches - its own static type (often `any`/interface{}
+		// for exactly this reason) is never reflected, since that would fail
+		// for an interface kind before the tag is ever consulted.
+		var item *Property
+		if field.Tag.Get("oneOf") != "" {
+			item = &Property{}
+			if err := applyOneOfTag(field, ctx, item); err != nil {
+				return nil, err
+			}
+		} else {
+			var err error
+			item, err = reflectSchemaByField(field, ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if description := field.Tag.Get("description"); description != "" {
+			item.Description = description
+		}
+		item.ReadOnly = directives.readOnly
+		item.WriteOnly = directives.writeOnly
+		item.Deprecated = directives.deprecated
+
+		// A named struct type is always reflected as a "#/$defs" reference
+		// (see reflectSchemaByType); flattening needs the actual expanded
+		// object underneath, so resolve the reference back to its def.
+		flattenTarget := item
+		if item.Ref != "" {
+			if def, ok := ctx.defs[strings.TrimPrefix(item.Ref, "#/$defs/")]; ok {
+				flattenTarget = def
+			}
+		}
+
+		if directives.flatten {
+			// A self-/mutually-referential struct's own $ref isn't resolvable
+			// here yet (its ctx.defs entry is only populated once the outer
+			// reflectSchemaByObject call that's flattening it returns), so
+			// there's nothing to flatten - reject it the same as the other
+			// invalid flatten combinations rather than silently keeping the
+			// field as an un-flattened $ref property.
+			if flattenTarget.Type != ObjectT {
+				return nil, fmt.Errorf("field %s: flatten requires an object-typed field whose schema is already resolvable (not a self- or mutually-referential struct)", field.Name)
+			}
+			// alias and required act on propName, but a flattened field never
+			// becomes a property under propName itself - its nested fields'
+			// own names take its place - so neither tag is meaningful here.
+			if len(directives.aliases) > 0 {
+				return nil, fmt.Errorf("field %s: alias cannot be combined with flatten", field.Name)
+			}
+			if field.Tag.Get("required") != "" {
+				return nil, fmt.Errorf("field %s: required tag cannot be combined with flatten", field.Name)
+			}
+			for nestedName, nestedProp := range flattenTarget.Properties {
+				flatName := directives.flattenPrefix + nestedName
+				if _, ok := properties[flatName]; ok {
+					return nil, fmt.Errorf("duplicate property name %s from flattened field %s", flatName, field.Name)
+				}
+				properties[flatName] = nestedProp
+			}
+			for _, nestedRequired := range flattenTarget.Required {
+				requiredFields = append(requiredFields, directives.flattenPrefix+nestedRequired)
+			}
+			for nestedCanonical, nestedAliases := range flattenTarget.Aliases {
+				aliases[directives.flattenPrefix+nestedCanonical] = nestedAliases
+			}
+		} else {
+			properties[propName] = item
+			if required {
+				requiredFields = append(requiredFields, propName)
+			}
+
+			if len(directives.aliases) > 0 {
+				aliases[propName] = directives.aliases
+			}
+
+			if s := field.Tag.Get("required"); s != "" {
+				forced, err := strconv.ParseBool(s)
+				if err != nil {
+					return nil, fmt.Errorf("invalid required field %v: %v", propName, err)
+				}
+				if forced {
+					if !contains(requiredFields, propName) {
+						requiredFields = append(requiredFields, propName)
+					}
+				} else {
+					requiredFields = removeString(requiredFields, propName)
+				}
+			}
+		}
+
+		if v := field.Tag.Get("enum"); v != "" {
+			enumStrings := strings.Split(v, ",")
+			enumValues := make([]any, len(enumStrings))
+
+			for j, value := range enumStrings {
+				value = strings.TrimSpace(value)
+
+				// Convert string values to appropriate types based on field type
+				switch field.Type.Kind() {
+				case reflect.String:
+					enumValues[j] = value
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+					intVal, err := strconv.Atoi(value)
+					if err != nil {
+						return nil, fmt.Errorf("enum value %q is not compatible with integer type %v", value, field.Type)
+					}
+					enumValues[j] = intVal
+				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+					uintVal, err := strconv.ParseUint(value, 10, 64)
+					if err != nil {
+						return nil, fmt.Errorf("enum value %q is not compatible with unsigned integer type %v", value, field.Type)
+					}
+					enumValues[j] = uintVal
+				case reflect.Float32, reflect.Float64:
+					floatVal, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						return nil, fmt.Errorf("enum value %q is not compatible with float type %v", value, field.Type)
+					}
+					enumValues[j] = floatVal
+				case reflect.Bool:
+					boolVal, err := strconv.ParseBool(value)
+					if err != nil {
+						return nil, fmt.Errorf("enum value %q is not compatible with boolean type %v", value, field.Type)
+					}
+					enumValues[j] = boolVal
+				default:
+					return nil, fmt.Errorf("unsupported type %v for enum validation", field.Type)
+				}
+			}
+			item.Enum = enumValues
+		}
+
+		// Handle default value
+		if defaultValue := field.Tag.Get("default"); defaultValue != "" {
+			// Convert string value to appropriate type based on field type
+			switch field.Type.Kind() {
+			case reflect.String:
+				item.Default = defaultValue
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				intVal, err := strconv.Atoi(defaultValue)
+				if err != nil {
+					return nil, fmt.Errorf("default value %q is not compatible with integer type %v", defaultValue, field.Type)
+				}
+				item.Default = intVal
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				uintVal, err := strconv.ParseUint(defaultValue, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("default value %q is not compatible with unsigned integer type %v", defaultValue, field.Type)
+				}
+				item.Default = uintVal
+			case reflect.Float32, reflect.Float64:
+				floatVal, err := strconv.ParseFloat(defaultValue, 64)
+				if err != nil {
+					return nil, fmt.Errorf("default value %q is not compatible with float type %v", defaultValue, field.Type)
+				}
+				item.Default = floatVal
+			case reflect.Bool:
+				boolVal, err := strconv.ParseBool(defaultValue)
+				if err != nil {
+					return nil, fmt.Errorf("default value %q is not compatible with boolean type %v", defaultValue, field.Type)
+				}
+				item.Default = boolVal
+			default:
+				// For complex types (arrays, objects), keep as string
+				// The consumer can parse it as needed
+				item.Default = defaultValue
+			}
+		}
+
+		if err := applyValidationTags(field, item); err != nil {
+			return nil, err
+		}
+
+		if v := field.Tag.Get("const"); v != "" {
+			kind := field.Type.Kind()
+			if kind == reflect.Ptr {
+				kind = field.Type.Elem().Kind()
+			}
+			converted, err := convertScalarTag(v, kind)
+			if err != nil {
+				return nil, fmt.Errorf("const value %q on field %s: %v", v, field.Name, err)
+			}
+			item.Const = converted
+		}
+	}
+
+	for _, field := range anonymousFields {
+		object, err := reflectSchemaByObject(field.Type, ctx)
+		if err != nil {
+			return nil, err
+		}
+		for propName, propValue := range object.Properties {
+			if _, ok := properties[propName]; ok {
+				return nil, fmt.Errorf("duplicate property name %s in anonymous struct", propName)
+			}
+			properties[propName] = propValue
+		}
+		requiredFields = append(requiredFields, object.Required...)
+		for canonical, names := range object.Aliases {
+			aliases[canonical] = names
+		}
+	}
+
+	property := &Property{
+		Type:                 ObjectT,
+		Properties:           properties,
+		Required:             requiredFields,
+		AdditionalProperties: additionalProps,
+	}
+	if len(aliases) > 0 {
+		property.Aliases = aliases
+	}
+	return property, nil
+}
+
+// convertScalarTag parses a tag's raw string value into the Go type
+// matching kind, the same per-Kind conversion the `enum` and `default` tags
+// already use. Without it, a stored tag value compares unequal (via %v
+// formatting) to a same-valued but differently-typed decoded JSON value,
+// e.g. a `const:"3.0"` float tag staying the string "3.0" instead of 3.0.
+func convertScalarTag(value string, kind reflect.Kind) (any, error) {
+	switch kind {
+	case reflect.String:
+		return value, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.Atoi(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	default:
+		return nil, fmt.Errorf("unsupported kind %v", kind)
+	}
+}
+
+// applyValidationTags reads the Draft-04/07 validation keyword tags off a
+// struct field and applies them to the already-reflected Property.
+func applyValidationTags(field reflect.StructField, item *Property) error {
+	if v := field.Tag.Get("minLength"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid minLength on field %s: %v", field.Name, err)
+		}
+		item.MinLength = &n
+	}
+	if v := field.Tag.Get("maxLength"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid maxLength on field %s: %v", field.Name, err)
+		}
+		item.MaxLength = &n
+	}
+	if v := field.Tag.Get("pattern"); v != "" {
+		item.Pattern = v
+	}
+	if v := field.Tag.Get("format"); v != "" {
+		item.Format = v
+	}
+
+	for tag, dst := range map[string]**float64{
+		"minimum":          &item.Minimum,
+		"maximum":          &item.Maximum,
+		"exclusiveMinimum": &item.ExclusiveMinimum,
+		"exclusiveMaximum": &item.ExclusiveMaximum,
+		"multipleOf":       &item.MultipleOf,
+	} {
+		if v := field.Tag.Get(tag); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s on field %s: %v", tag, field.Name, err)
+			}
+			*dst = &f
+		}
+	}
+
+	if v := field.Tag.Get("minItems"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid minItems on field %s: %v", field.Name, err)
+		}
+		item.MinItems = &n
+	}
+	if v := field.Tag.Get("maxItems"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid maxItems on field %s: %v", field.Name, err)
+		}
+		item.MaxItems = &n
+	}
+	if v := field.Tag.Get("uniqueItems"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid uniqueItems on field %s: %v", field.Name, err)
+		}
+		item.UniqueItems = b
+	}
+	return nil
+}
+
+// applyOneOfTag handles the `oneOf:"TypeA,TypeB,TypeC"` struct-tag form of
+// union types, resolving each branch against the registered name table.
+func applyOneOfTag(field reflect.StructField, ctx *genContext, item *Property) error {
+	v := field.Tag.Get("oneOf")
+	if v == "" {
+		return nil
+	}
+	discriminator := field.Tag.Get("discriminator")
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		branchType, ok := namedTypeRegistry[name]
+		if !ok {
+			return fmt.Errorf("oneOf branch %q on field %s is not registered, see RegisterNamedType", name, field.Name)
+		}
+		if err := addOneOfBranch(item, branchType, name, discriminator, ctx); err != nil {
+			return err
+		}
+	}
+	item.Type = ""
+	return nil
+}
+
+// addOneOfBranch reflects branchType and appends it to item.OneOf, stamping
+// in the discriminator const property when one is configured. Shared by the
+// `oneOf` struct-tag form and the RegisterOneOf interface-field form so the
+// two stay in sync.
+func addOneOfBranch(item *Property, branchType reflect.Type, branchName, discriminator string, ctx *genContext) error {
+	branch, err := reflectSchemaByType(branchType, ctx)
+	if err != nil {
+		return err
+	}
+	if discriminator != "" {
+		if branch.Properties == nil {
+			branch.Properties = make(map[string]*Property)
+		}
+		branch.Properties[discriminator] = &Property{Type: String, Const: branchName}
+	}
+	item.OneOf = append(item.OneOf, branch)
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, item := range s {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(s []string, v string) []string {
+	out := s[:0]
+	for _, item := range s {
+		if item != v {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// reflectSchemaByField reflects a single struct field, first giving the
+// interface/union registry a chance to handle it before falling through to
+// the plain type-based reflection.
+func reflectSchemaByField(field reflect.StructField, ctx *genContext) (*Property, error) {
+	if field.Type.Kind() == reflect.Interface {
+		if impls, ok := oneOfRegistry[field.Type]; ok {
+			item := &Property{}
+			discriminator := field.Tag.Get("discriminator")
+			for _, implType := range impls {
+				if err := addOneOfBranch(item, implType, implType.Name(), discriminator, ctx); err != nil {
+					return nil, err
+				}
+			}
+			return item, nil
+		}
+	}
+	return reflectSchemaByType(field.Type, ctx)
+}
+
+func reflectSchemaByType(t reflect.Type, ctx *genContext) (*Property, error) {
+	if marshaler, ok := lookupSchemaMarshaler(t); ok {
+		return marshaler.JSONSchema(), nil
+	}
+	if prop, ok := typeRegistry[t]; ok {
+		cp := *prop
+		return &cp, nil
+	}
+
+	s := &Property{}
+
+	switch t.Kind() {
+	case reflect.String:
+		s.Type = String
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s.Type = Integer
+	case reflect.Float32, reflect.Float64:
+		s.Type = Number
+	case reflect.Bool:
+		s.Type = Boolean
+	case reflect.Slice, reflect.Array:
+		s.Type = Array
+		items, err := reflectSchemaByType(t.Elem(), ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.Items = items
+	case reflect.Struct:
+		uid := getTypeUUID(t)
+		named := t.Name() != "" && t.PkgPath() != ""
+
+		if named {
+			if refName, ok := ctx.defNames[uid]; ok {
+				// Either still being expanded (a true cycle) or already
+				// fully emitted under $defs earlier in this same call.
+				ctx.referenced[uid] = true
+				return &Property{Ref: "#/$defs/" + refName}, nil
+			}
+			ctx.defNames[uid] = ctx.reserveDefName(t.Name())
+		}
+
+		object, err := reflectSchemaByObject(t, ctx)
+		if err != nil {
+			return nil, err
+		}
+		object.Type = ObjectT
+
+		if named {
+			// Every named struct type is emitted once under $defs and every
+			// occurrence - including the first - becomes a $ref to it, so
+			// that two sibling fields of the same type (e.g. Shipping and
+			// Billing both typed addr) share one definition instead of each
+			// getting their own independently inlined copy.
+			refName := ctx.defNames[uid]
+			ctx.defs[refName] = object
+			return &Property{Ref: "#/$defs/" + refName}, nil
+		}
+		s = object
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key type %s is not supported", t.Key().Kind())
+		}
+		object := &Property{
+			Type: ObjectT,
+		}
+		s = object
+	case reflect.Ptr:
+		p, err := reflectSchemaByType(t.Elem(), ctx)
+		if err != nil {
+			return nil, err
+		}
+		s = p
+	case reflect.Invalid, reflect.Uintptr, reflect.Complex64, reflect.Complex128,
+		reflect.Chan, reflect.Func, reflect.Interface,
+		reflect.UnsafePointer:
+		return nil, fmt.Errorf("unsupported type: %s", t.Kind().String())
+	default:
+	}
+	return s, nil
+}
@@ -0,0 +1,348 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type simpleReq struct {
+	Name string `json:"name" minLength:"2" maxLength:"10"`
+	Age  int    `json:"age,omitempty" minimum:"0" maximum:"150"`
+}
+
+func TestGenerateSchemaFromReqStruct_ValidationKeywords(t *testing.T) {
+	schema, err := generateSchemaFromReqStruct(simpleReq{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+
+	name := schema.Properties["name"]
+	if name == nil || name.MinLength == nil || *name.MinLength != 2 || name.MaxLength == nil || *name.MaxLength != 10 {
+		t.Fatalf("name property missing minLength/maxLength: %+v", name)
+	}
+	if !contains(schema.Required, "name") {
+		t.Fatalf("expected name to be required, got %v", schema.Required)
+	}
+	if contains(schema.Required, "age") {
+		t.Fatalf("age has omitempty, should not be required")
+	}
+}
+
+type treeNode struct {
+	Value    string      `json:"value"`
+	Children []*treeNode `json:"children,omitempty"`
+}
+
+func TestGenerateSchemaFromReqStruct_SelfReferential(t *testing.T) {
+	schema, err := generateSchemaFromReqStruct(treeNode{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+
+	children := schema.Properties["children"]
+	if children == nil || children.Type != Array {
+		t.Fatalf("expected children array property, got %+v", children)
+	}
+	if children.Items == nil || children.Items.Ref == "" {
+		t.Fatalf("expected self-referential items to be a $ref, got %+v", children.Items)
+	}
+	defName := children.Items.Ref[len("#/$defs/"):]
+	def, ok := schema.Defs[defName]
+	if !ok {
+		t.Fatalf("expected schema.Defs[%q] to exist, got %v", defName, schema.Defs)
+	}
+	if _, ok := def.Properties["value"]; !ok {
+		t.Fatalf("expected def to carry the node's own properties, got %+v", def)
+	}
+}
+
+func TestGenContext_ReserveDefName_DisambiguatesCollisions(t *testing.T) {
+	ctx := newGenContext()
+	first := ctx.reserveDefName("Node")
+	second := ctx.reserveDefName("Node")
+	if first == second {
+		t.Fatalf("expected distinct names for two reservations of the same base name, got %q twice", first)
+	}
+	if first != "Node" || second != "Node2" {
+		t.Fatalf("expected Node/Node2, got %q/%q", first, second)
+	}
+}
+
+type flattenInner struct {
+	Foo string `json:"foo"`
+}
+
+func TestReflectSchemaByObject_FlattenWithAliasIsRejected(t *testing.T) {
+	type withFlattenAlias struct {
+		Inner flattenInner `json:"inner" mcp:"flatten,alias=legacy_inner"`
+	}
+	if _, err := generateSchemaFromReqStruct(withFlattenAlias{}); err == nil {
+		t.Fatalf("expected an error combining flatten with alias")
+	}
+}
+
+func TestReflectSchemaByObject_FlattenWithRequiredTagIsRejected(t *testing.T) {
+	type withFlattenRequired struct {
+		Inner flattenInner `json:"inner" mcp:"flatten" required:"true"`
+	}
+	if _, err := generateSchemaFromReqStruct(withFlattenRequired{}); err == nil {
+		t.Fatalf("expected an error combining flatten with required")
+	}
+}
+
+func TestReflectSchemaByObject_Flatten(t *testing.T) {
+	type withFlatten struct {
+		Inner flattenInner `json:"inner" mcp:"flatten=inner_"`
+	}
+	schema, err := generateSchemaFromReqStruct(withFlatten{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+	if _, ok := schema.Properties["inner_foo"]; !ok {
+		t.Fatalf("expected flattened property inner_foo, got %v", schema.Properties)
+	}
+	if _, ok := schema.Properties["inner"]; ok {
+		t.Fatalf("container property should not survive flattening, got %v", schema.Properties)
+	}
+}
+
+type logQLQuery struct {
+	Query string `json:"query"`
+}
+
+type structuredQuery struct {
+	Query string `json:"query"`
+	Start int64  `json:"start"`
+	End   int64  `json:"end"`
+}
+
+func TestOneOfTag_OnInterfaceField(t *testing.T) {
+	RegisterNamedType("LogQLQuery", logQLQuery{})
+	RegisterNamedType("StructuredQuery", structuredQuery{})
+
+	type queryTool struct {
+		Q any `json:"q" oneOf:"LogQLQuery,StructuredQuery"`
+	}
+
+	schema, err := generateSchemaFromReqStruct(queryTool{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct on an interface field with a oneOf tag: %v", err)
+	}
+	q := schema.Properties["q"]
+	if q == nil || len(q.OneOf) != 2 {
+		t.Fatalf("expected two oneOf branches, got %+v", q)
+	}
+}
+
+type shape interface{ isShape() }
+type circle struct{ Radius float64 }
+type square struct{ Side float64 }
+
+func (circle) isShape() {}
+func (square) isShape() {}
+
+func TestRegisterOneOf(t *testing.T) {
+	RegisterOneOf((*shape)(nil), circle{}, square{})
+
+	type withShape struct {
+		Shape shape `json:"shape"`
+	}
+	schema, err := generateSchemaFromReqStruct(withShape{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+	s := schema.Properties["shape"]
+	if s == nil || len(s.OneOf) != 2 {
+		t.Fatalf("expected two oneOf branches for the registered interface, got %+v", s)
+	}
+}
+
+func TestValidate_RequiredEnumPattern(t *testing.T) {
+	schema, err := generateSchemaFromReqStruct(simpleReq{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+
+	if err := Validate(schema, map[string]any{}); err == nil {
+		t.Fatalf("expected missing required field to fail validation")
+	}
+
+	if err := Validate(schema, map[string]any{"name": "ok"}); err != nil {
+		t.Fatalf("expected valid args to pass, got %v", err)
+	}
+
+	if err := Validate(schema, map[string]any{"name": "x"}); err == nil {
+		t.Fatalf("expected minLength violation to fail validation")
+	}
+}
+
+func TestValidate_ResolvesRefForSelfReferentialType(t *testing.T) {
+	schema, err := generateSchemaFromReqStruct(treeNode{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+
+	args := map[string]any{
+		"value": "root",
+		"children": []any{
+			map[string]any{
+				// missing "value", which is required on treeNode - only
+				// catchable if validation follows the $ref into the
+				// recursive node definition instead of stopping at it.
+			},
+		},
+	}
+	err = Validate(schema, args)
+	if err == nil {
+		t.Fatalf("expected validation to catch a missing required field nested through a $ref")
+	}
+}
+
+func TestValidate_MultipleOfToleratesFloatRounding(t *testing.T) {
+	multipleOf := 0.1
+	schema := &InputSchema{
+		Type: Object,
+		Properties: map[string]*Property{
+			"amount": {Type: Number, MultipleOf: &multipleOf},
+		},
+	}
+	if err := Validate(schema, map[string]any{"amount": 0.3}); err != nil {
+		t.Fatalf("expected 0.3 to be treated as a multiple of 0.1 despite float rounding, got %v", err)
+	}
+	if err := Validate(schema, map[string]any{"amount": 0.25}); err == nil {
+		t.Fatalf("expected 0.25 to fail multipleOf 0.1")
+	}
+}
+
+type addr struct {
+	Street string `json:"street"`
+}
+
+func TestReflectSchemaByType_DedupsSiblingStructOccurrences(t *testing.T) {
+	type withTwoAddrs struct {
+		Shipping addr `json:"shipping"`
+		Billing  addr `json:"billing"`
+	}
+	schema, err := generateSchemaFromReqStruct(withTwoAddrs{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+
+	shipping := schema.Properties["shipping"]
+	billing := schema.Properties["billing"]
+	if shipping == nil || shipping.Ref == "" || billing == nil || billing.Ref == "" {
+		t.Fatalf("expected both sibling addr fields to be $refs, got shipping=%+v billing=%+v", shipping, billing)
+	}
+	if shipping.Ref != billing.Ref {
+		t.Fatalf("expected both sibling addr fields to share one $defs entry, got %q and %q", shipping.Ref, billing.Ref)
+	}
+	defName := shipping.Ref[len("#/$defs/"):]
+	if _, ok := schema.Defs[defName]; !ok {
+		t.Fatalf("expected schema.Defs[%q] to exist, got %v", defName, schema.Defs)
+	}
+}
+
+type nestedAliasInner struct {
+	Name string `json:"name" mcp:"alias=full_name"`
+}
+
+type nestedAliasOuter struct {
+	Inner nestedAliasInner `json:"inner"`
+}
+
+func TestBindArguments_AppliesAliasInsideNestedStruct(t *testing.T) {
+	schema, err := generateSchemaFromReqStruct(nestedAliasOuter{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+
+	var dst nestedAliasOuter
+	raw := json.RawMessage(`{"inner":{"full_name":"ada"}}`)
+	if err := BindArguments(schema, raw, &dst); err != nil {
+		t.Fatalf("BindArguments with nested alias: %v", err)
+	}
+	if dst.Inner.Name != "ada" {
+		t.Fatalf("expected nested alias to bind into Inner.Name, got %q", dst.Inner.Name)
+	}
+}
+
+func TestGenerateSchemaFromReqStruct_ConstMatchesDecodedType(t *testing.T) {
+	type withFloatConst struct {
+		Version float64 `json:"version" const:"3.0"`
+	}
+	schema, err := generateSchemaFromReqStruct(withFloatConst{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+	if err := Validate(schema, map[string]any{"version": 3.0}); err != nil {
+		t.Fatalf("expected decoded float 3.0 to match const \"3.0\", got %v", err)
+	}
+	if err := Validate(schema, map[string]any{"version": 3.1}); err == nil {
+		t.Fatalf("expected 3.1 to fail const 3.0")
+	}
+}
+
+func TestGenerateSchemaFromReqStruct_ConstOnPointerField(t *testing.T) {
+	type withPtrConst struct {
+		Version *int `json:"version,omitempty" const:"3"`
+	}
+	schema, err := generateSchemaFromReqStruct(withPtrConst{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct on a pointer field with const: %v", err)
+	}
+	if schema.Properties["version"].Const != 3 {
+		t.Fatalf("expected const 3, got %v", schema.Properties["version"].Const)
+	}
+}
+
+type selfRefFlatten struct {
+	Name  string          `json:"name"`
+	Child *selfRefFlatten `json:"child,omitempty" mcp:"flatten"`
+}
+
+func TestReflectSchemaByObject_FlattenOnSelfReferentialFieldIsRejected(t *testing.T) {
+	if _, err := generateSchemaFromReqStruct(selfRefFlatten{}); err == nil {
+		t.Fatalf("expected an error flattening a self-referential field")
+	}
+}
+
+func TestValidate_Format(t *testing.T) {
+	schema := &InputSchema{
+		Type: Object,
+		Properties: map[string]*Property{
+			"email": {Type: String, Format: "email"},
+		},
+	}
+	if err := Validate(schema, map[string]any{"email": "ada@example.com"}); err != nil {
+		t.Fatalf("expected valid email to pass, got %v", err)
+	}
+	if err := Validate(schema, map[string]any{"email": "not-an-email-at-all"}); err == nil {
+		t.Fatalf("expected invalid email to fail format validation")
+	}
+}
+
+func TestBindArguments_DefaultsAndAliases(t *testing.T) {
+	type bindReq struct {
+		Name string `json:"name" mcp:"alias=full_name" default:"anonymous"`
+	}
+	schema, err := generateSchemaFromReqStruct(bindReq{})
+	if err != nil {
+		t.Fatalf("generateSchemaFromReqStruct: %v", err)
+	}
+
+	var dst bindReq
+	if err := BindArguments(schema, json.RawMessage(`{"full_name":"ada"}`), &dst); err != nil {
+		t.Fatalf("BindArguments with alias: %v", err)
+	}
+	if dst.Name != "ada" {
+		t.Fatalf("expected alias to bind into Name, got %q", dst.Name)
+	}
+
+	var withDefault bindReq
+	if err := BindArguments(schema, json.RawMessage(`{}`), &withDefault); err != nil {
+		t.Fatalf("BindArguments with default: %v", err)
+	}
+	if withDefault.Name != "anonymous" {
+		t.Fatalf("expected default value to be applied, got %q", withDefault.Name)
+	}
+}
@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaMarshaler lets a type take full control of its own generated schema,
+// bypassing reflection entirely. Types that need a representation reflection
+// can't infer correctly (custom scalars, wire formats, etc.) should implement
+// it rather than being special-cased in reflectSchemaByType.
+type SchemaMarshaler interface {
+	JSONSchema() *Property
+}
+
+var schemaMarshalerType = reflect.TypeOf((*SchemaMarshaler)(nil)).Elem()
+
+// typeRegistry holds Property overrides registered via RegisterType, consulted
+// before reflection in reflectSchemaByType.
+var typeRegistry = make(map[reflect.Type]*Property)
+
+// namedTypeRegistry backs the `oneOf:"TypeA,TypeB"` struct-tag form of unions,
+// mapping a branch name to its concrete type.
+var namedTypeRegistry = make(map[string]reflect.Type)
+
+// oneOfRegistry backs RegisterOneOf, mapping an interface type to the
+// concrete implementations that can satisfy it in a schema.
+var oneOfRegistry = make(map[reflect.Type][]reflect.Type)
+
+// RegisterType installs a fixed Property to use whenever t is encountered
+// during schema reflection, overriding whatever reflectSchemaByType would
+// otherwise infer. It's most useful for types whose Go representation
+// doesn't map cleanly onto JSON, e.g. time.Duration or a UUID.
+func RegisterType(t reflect.Type, schema *Property) {
+	typeRegistry[t] = schema
+}
+
+// RegisterNamedType gives a type a name that `oneOf:"Name,..."` struct tags
+// can refer to. RegisterOneOf does this automatically for interface
+// implementations; call this directly when using the tag form on its own.
+func RegisterNamedType(name string, t any) {
+	namedTypeRegistry[name] = indirectType(reflect.TypeOf(t))
+}
+
+// RegisterOneOf declares that iface (a pointer to an interface value, e.g.
+// (*Shape)(nil)) may be satisfied by any of impls when it shows up as a
+// struct field. Each implementation is also registered under its type name
+// via RegisterNamedType so it can be referenced from an `oneOf` tag too.
+func RegisterOneOf(iface any, impls ...any) {
+	ifaceType := reflect.TypeOf(iface).Elem()
+	implTypes := make([]reflect.Type, 0, len(impls))
+	for _, impl := range impls {
+		t := indirectType(reflect.TypeOf(impl))
+		implTypes = append(implTypes, t)
+		RegisterNamedType(t.Name(), impl)
+	}
+	oneOfRegistry[ifaceType] = implTypes
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// lookupSchemaMarshaler reports whether t (or *t) implements SchemaMarshaler
+// and returns a usable instance if so.
+func lookupSchemaMarshaler(t reflect.Type) (SchemaMarshaler, bool) {
+	if t.Implements(schemaMarshalerType) {
+		return reflect.New(t).Elem().Interface().(SchemaMarshaler), true
+	}
+	if reflect.PtrTo(t).Implements(schemaMarshalerType) {
+		return reflect.New(t).Interface().(SchemaMarshaler), true
+	}
+	return nil, false
+}
+
+func init() {
+	RegisterType(reflect.TypeOf(time.Time{}), &Property{Type: String, Format: "date-time"})
+	RegisterType(reflect.TypeOf(time.Duration(0)), &Property{Type: String, Description: "a Go duration string, e.g. \"1h30m\""})
+	RegisterType(reflect.TypeOf(json.RawMessage{}), &Property{})
+	RegisterType(reflect.TypeOf(net.IP{}), &Property{Type: String, Format: "ipv4"})
+	RegisterType(reflect.TypeOf(url.URL{}), &Property{Type: String, Format: "uri"})
+	RegisterType(reflect.TypeOf(uuid.UUID{}), &Property{Type: String, Format: "uuid"})
+	RegisterType(reflect.TypeOf(big.Int{}), &Property{Type: String, Description: "a base-10 arbitrary-precision integer"})
+	RegisterType(reflect.TypeOf(big.Float{}), &Property{Type: String, Description: "a base-10 arbitrary-precision float"})
+}
@@ -0,0 +1,310 @@
+package protocol
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// patternCache memoizes compiled patterns so a `pattern` constraint checked
+// against every element of a large array doesn't recompile the same regexp
+// once per element.
+var patternCache sync.Map // pattern string -> *regexp.Regexp (or compile error)
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patternCache.Load(pattern); ok {
+		if re, ok := cached.(*regexp.Regexp); ok {
+			return re, nil
+		}
+		return nil, cached.(error)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		patternCache.Store(pattern, err)
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// ValidationError collects every constraint violation found while checking
+// arguments against a schema, rather than failing on the first one. Each
+// entry's Pointer is a JSON Pointer (RFC 6901) into the argument document.
+type ValidationError struct {
+	Violations []Violation
+}
+
+type Violation struct {
+	Pointer string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Violations) == 0 {
+		return "validation failed"
+	}
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = fmt.Sprintf("%s: %s", v.Pointer, v.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) add(pointer, format string, args ...any) {
+	e.Violations = append(e.Violations, Violation{
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate checks args against schema's declared properties and constraints,
+// before a tool handler ever sees them. It returns a *ValidationError (as a
+// plain error) listing every violation found, or nil if args satisfies the
+// schema.
+func Validate(schema *InputSchema, args map[string]any) error {
+	result := &ValidationError{}
+	validateObject(result, "", schema.Properties, schema.Required, schema.AdditionalProperties, args, schema.Defs)
+	if len(result.Violations) == 0 {
+		return nil
+	}
+	return result
+}
+
+func validateObject(result *ValidationError, pointer string, properties map[string]*Property, required []string, additionalProperties *bool, args map[string]any, defs map[string]*Property) {
+	for _, name := range required {
+		if _, ok := args[name]; !ok {
+			result.add(childPointer(pointer, name), "required property is missing")
+		}
+	}
+
+	for name, value := range args {
+		prop, ok := properties[name]
+		if !ok {
+			if additionalProperties != nil && !*additionalProperties {
+				result.add(childPointer(pointer, name), "additional property %q is not allowed", name)
+			}
+			continue
+		}
+		validateValue(result, childPointer(pointer, name), prop, value, defs)
+	}
+}
+
+// resolveRef follows a "#/$defs/Name" reference into defs. A schema built by
+// reflectSchemaByType never nests a $ref under other keywords, so swapping
+// prop wholesale for its resolved target is sufficient.
+func resolveRef(prop *Property, defs map[string]*Property) *Property {
+	for prop != nil && prop.Ref != "" {
+		name := strings.TrimPrefix(prop.Ref, "#/$defs/")
+		resolved, ok := defs[name]
+		if !ok {
+			return prop
+		}
+		prop = resolved
+	}
+	return prop
+}
+
+func validateValue(result *ValidationError, pointer string, prop *Property, value any, defs map[string]*Property) {
+	if prop == nil || value == nil {
+		return
+	}
+	prop = resolveRef(prop, defs)
+	if prop == nil {
+		return
+	}
+
+	if len(prop.Enum) > 0 && !containsAny(prop.Enum, value) {
+		result.add(pointer, "value %v is not one of the allowed enum values", value)
+	}
+	if prop.Const != nil && fmt.Sprintf("%v", prop.Const) != fmt.Sprintf("%v", value) {
+		result.add(pointer, "value %v does not match const %v", value, prop.Const)
+	}
+
+	switch prop.Type {
+	case String:
+		s, ok := value.(string)
+		if !ok {
+			result.add(pointer, "expected a string, got %T", value)
+			return
+		}
+		if prop.MinLength != nil && len(s) < *prop.MinLength {
+			result.add(pointer, "length %d is less than minLength %d", len(s), *prop.MinLength)
+		}
+		if prop.MaxLength != nil && len(s) > *prop.MaxLength {
+			result.add(pointer, "length %d is greater than maxLength %d", len(s), *prop.MaxLength)
+		}
+		if prop.Pattern != "" {
+			re, err := compilePattern(prop.Pattern)
+			if err != nil {
+				result.add(pointer, "invalid pattern %q: %v", prop.Pattern, err)
+			} else if !re.MatchString(s) {
+				result.add(pointer, "value does not match pattern %q", prop.Pattern)
+			}
+		}
+		if prop.Format != "" {
+			if err := validateFormat(prop.Format, s); err != nil {
+				result.add(pointer, "value does not satisfy format %q: %v", prop.Format, err)
+			}
+		}
+	case Number, Integer:
+		n, ok := toFloat64(value)
+		if !ok {
+			result.add(pointer, "expected a number, got %T", value)
+			return
+		}
+		validateNumber(result, pointer, prop, n)
+	case Array:
+		items, ok := value.([]any)
+		if !ok {
+			result.add(pointer, "expected an array, got %T", value)
+			return
+		}
+		if prop.MinItems != nil && len(items) < *prop.MinItems {
+			result.add(pointer, "item count %d is less than minItems %d", len(items), *prop.MinItems)
+		}
+		if prop.MaxItems != nil && len(items) > *prop.MaxItems {
+			result.add(pointer, "item count %d is greater than maxItems %d", len(items), *prop.MaxItems)
+		}
+		if prop.UniqueItems && hasDuplicates(items) {
+			result.add(pointer, "array items must be unique")
+		}
+		for i, item := range items {
+			validateValue(result, fmt.Sprintf("%s/%d", pointer, i), prop.Items, item, defs)
+		}
+	case ObjectT:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			result.add(pointer, "expected an object, got %T", value)
+			return
+		}
+		validateObject(result, pointer, prop.Properties, prop.Required, prop.AdditionalProperties, obj, defs)
+	}
+
+	if len(prop.OneOf) > 0 {
+		matches := 0
+		for _, branch := range prop.OneOf {
+			branchResult := &ValidationError{}
+			validateValue(branchResult, pointer, branch, value, defs)
+			if len(branchResult.Violations) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			result.add(pointer, "value must match exactly one oneOf branch, matched %d", matches)
+		}
+	}
+}
+
+// validateFormat enforces the subset of JSON Schema "format" hints that
+// reflectSchemaByType/RegisterType actually emit (see schema_registry.go's
+// init). An unrecognized format is accepted rather than rejected - it's an
+// annotation-only hint to clients until this function knows how to check it.
+func validateFormat(format, s string) error {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err
+	case "email":
+		_, err := mail.ParseAddress(s)
+		return err
+	case "uri":
+		u, err := url.Parse(s)
+		if err != nil {
+			return err
+		}
+		if !u.IsAbs() {
+			return fmt.Errorf("not an absolute URI")
+		}
+		return nil
+	case "uuid":
+		_, err := uuid.Parse(s)
+		return err
+	case "ipv4":
+		ip := net.ParseIP(s)
+		if ip == nil || ip.To4() == nil {
+			return fmt.Errorf("not a valid IPv4 address")
+		}
+		return nil
+	case "ipv6":
+		ip := net.ParseIP(s)
+		if ip == nil || ip.To4() != nil {
+			return fmt.Errorf("not a valid IPv6 address")
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+func validateNumber(result *ValidationError, pointer string, prop *Property, n float64) {
+	if prop.Minimum != nil && n < *prop.Minimum {
+		result.add(pointer, "value %v is less than minimum %v", n, *prop.Minimum)
+	}
+	if prop.Maximum != nil && n > *prop.Maximum {
+		result.add(pointer, "value %v is greater than maximum %v", n, *prop.Maximum)
+	}
+	if prop.ExclusiveMinimum != nil && n <= *prop.ExclusiveMinimum {
+		result.add(pointer, "value %v is not greater than exclusiveMinimum %v", n, *prop.ExclusiveMinimum)
+	}
+	if prop.ExclusiveMaximum != nil && n >= *prop.ExclusiveMaximum {
+		result.add(pointer, "value %v is not less than exclusiveMaximum %v", n, *prop.ExclusiveMaximum)
+	}
+	if prop.MultipleOf != nil && *prop.MultipleOf != 0 {
+		// math.Mod rather than comparing n/MultipleOf against its rounded
+		// int64: float64 division/rounding makes e.g. 0.3 look like a
+		// non-integer multiple of 0.1 despite being one.
+		const epsilon = 1e-9
+		remainder := math.Abs(math.Mod(n, *prop.MultipleOf))
+		if remainder > epsilon && math.Abs(remainder-math.Abs(*prop.MultipleOf)) > epsilon {
+			result.add(pointer, "value %v is not a multiple of %v", n, *prop.MultipleOf)
+		}
+	}
+}
+
+func childPointer(parent, name string) string {
+	return parent + "/" + name
+}
+
+func containsAny(haystack []any, needle any) bool {
+	needleStr := fmt.Sprintf("%v", needle)
+	for _, v := range haystack {
+		if fmt.Sprintf("%v", v) == needleStr {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDuplicates(items []any) bool {
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		key := fmt.Sprintf("%v", item)
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+	}
+	return false
+}
+
+func toFloat64(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}